@@ -0,0 +1,63 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/EDXFund/Validator/common"
+	"github.com/EDXFund/Validator/common/math"
+)
+
+const (
+	// BaseFeeChangeDenominator bounds the amount the base fee can change between blocks.
+	BaseFeeChangeDenominator = 8
+
+	// ElasticityMultiplier bounds the maximum gas limit an EIP-1559 block may have relative
+	// to its gas target.
+	ElasticityMultiplier = 2
+
+	// InitialBaseFee is the base fee used for the first block that enables the base-fee
+	// field, mirroring the value chosen for EIP-1559 on mainnet.
+	InitialBaseFee = 1000000000
+)
+
+// CalcBaseFee calculates the base fee of the header following the parent header's
+// base fee, ported from EIP-1559. If the parent header does not yet carry a base
+// fee (the field was activated starting with its child), InitialBaseFee is used.
+func CalcBaseFee(parent *Header) *big.Int {
+	if parent.BaseFee == nil {
+		return big.NewInt(InitialBaseFee)
+	}
+
+	parentGasTarget := parent.GasLimit / ElasticityMultiplier
+	if parentGasTarget == 0 {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+	if parent.GasUsed == parentGasTarget {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+
+	var (
+		num   = new(big.Int)
+		denom = new(big.Int).SetUint64(parentGasTarget)
+	)
+
+	if parent.GasUsed > parentGasTarget {
+		// parentGasUsed > parentGasTarget: base fee increases, capped at 12.5% per block.
+		num.SetUint64(parent.GasUsed - parentGasTarget)
+		num.Mul(num, parent.BaseFee)
+		num.Div(num, denom)
+		num.Div(num, big.NewInt(BaseFeeChangeDenominator))
+		baseFeeDelta := math.BigMax(num, common.Big1)
+
+		return new(big.Int).Add(parent.BaseFee, baseFeeDelta)
+	}
+
+	// parentGasUsed < parentGasTarget: base fee decreases, capped at 12.5% per block.
+	num.SetUint64(parentGasTarget - parent.GasUsed)
+	num.Mul(num, parent.BaseFee)
+	num.Div(num, denom)
+	num.Div(num, big.NewInt(BaseFeeChangeDenominator))
+
+	baseFee := new(big.Int).Sub(parent.BaseFee, num)
+	return math.BigMax(baseFee, common.Big1)
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCalcBaseFeeInitial(t *testing.T) {
+	parent := &Header{GasLimit: 10_000_000, GasUsed: 5_000_000}
+	if got := CalcBaseFee(parent); got.Cmp(big.NewInt(InitialBaseFee)) != 0 {
+		t.Errorf("got %v, want %v", got, InitialBaseFee)
+	}
+}
+
+func TestCalcBaseFeeUnchangedAtTarget(t *testing.T) {
+	parent := &Header{GasLimit: 10_000_000, GasUsed: 5_000_000, BaseFee: big.NewInt(1_000_000_000)}
+	got := CalcBaseFee(parent)
+	if got.Cmp(parent.BaseFee) != 0 {
+		t.Errorf("got %v, want unchanged %v", got, parent.BaseFee)
+	}
+}
+
+func TestCalcBaseFeeIncreasesWhenAboveTarget(t *testing.T) {
+	parent := &Header{GasLimit: 10_000_000, GasUsed: 10_000_000, BaseFee: big.NewInt(1_000_000_000)}
+	got := CalcBaseFee(parent)
+	if got.Cmp(parent.BaseFee) <= 0 {
+		t.Errorf("got %v, want greater than parent base fee %v", got, parent.BaseFee)
+	}
+	// Fully saturated gas usage is clamped to a 12.5% increase.
+	want := new(big.Int).Add(parent.BaseFee, new(big.Int).Div(parent.BaseFee, big.NewInt(BaseFeeChangeDenominator)))
+	if got.Cmp(want) != 0 {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCalcBaseFeeDecreasesWhenBelowTarget(t *testing.T) {
+	parent := &Header{GasLimit: 10_000_000, GasUsed: 0, BaseFee: big.NewInt(1_000_000_000)}
+	got := CalcBaseFee(parent)
+	if got.Cmp(parent.BaseFee) >= 0 {
+		t.Errorf("got %v, want less than parent base fee %v", got, parent.BaseFee)
+	}
+}
+
+func TestCalcBaseFeeFloor(t *testing.T) {
+	parent := &Header{GasLimit: 10_000_000, GasUsed: 0, BaseFee: big.NewInt(1)}
+	got := CalcBaseFee(parent)
+	if got.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("got %v, want floor of 1", got)
+	}
+}
@@ -2,9 +2,12 @@ package types
 
 import (
 	"encoding/binary"
+	"fmt"
+	"hash"
 	"io"
 	"math/big"
 	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
@@ -14,14 +17,25 @@ import (
 
 	"github.com/EDXFund/Validator/crypto/sha3"
 	"github.com/EDXFund/Validator/rlp"
+	"github.com/EDXFund/Validator/trie"
 )
 
 var (
-	EmptyRootHash = DeriveSha(Transactions{})
+	// EmptyRootHash is the known root hash of an empty trie, i.e. the value of
+	// DeriveSha(Transactions{}). It is precomputed as a constant to avoid
+	// building an empty trie at package init time.
+	EmptyRootHash = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
 
 //	EmptyUncleHash = CalcUncleHash(nil)
 )
 
+// hasherPool holds LegacyKeccak256 hashers for rlpHash, so that Header.Hash,
+// which sits on the block-import and peer-relay hot paths, does not allocate
+// a fresh hash.Hash on every call.
+var hasherPool = sync.Pool{
+	New: func() interface{} { return sha3.NewKeccak256() },
+}
+
 type ShardStatus uint16
 
 var (
@@ -59,9 +73,12 @@ func (n *BlockNonce) UnmarshalText(input []byte) error {
 }
 
 func rlpHash(x interface{}) (h common.Hash) {
-	hw := sha3.NewKeccak256()
-	rlp.Encode(hw, x)
-	hw.Sum(h[:0])
+	sha := hasherPool.Get().(hash.Hash)
+	defer hasherPool.Put(sha)
+	sha.Reset()
+
+	rlp.Encode(sha, x)
+	sha.Sum(h[:0])
 	return h
 }
 
@@ -104,6 +121,13 @@ type Header struct {
 	Extra       []byte      `json:"extraData"        gencodec:"required"`
 	MixDigest   common.Hash `json:"mixHash"          gencodec:"required"`
 	Nonce       BlockNonce  `json:"nonce"            gencodec:"required"`
+
+	// BaseFee was added by EIP-1559 and is ignored in legacy headers.
+	BaseFee *big.Int `json:"baseFeePerGas" rlp:"optional"`
+
+	// WithdrawalsHash is the root of the withdrawals trie, absent on blocks
+	// that carry no withdrawals section.
+	WithdrawalsHash *common.Hash `json:"withdrawalsRoot" rlp:"optional"`
 }
 
 type HeaderMarshal struct {
@@ -123,6 +147,8 @@ type HeaderMarshal struct {
 	Extra       hexutil.Bytes  `json:"extraData"        gencodec:"required"`
 	MixDigest   common.Hash    `json:"mixHash"          gencodec:"required"`
 	Nonce       BlockNonce     `json:"nonce"            gencodec:"required"`
+	BaseFee     *hexutil.Big   `json:"baseFeePerGas"    rlp:"optional"`
+	WithdrawalsHash *common.Hash `json:"withdrawalsRoot" rlp:"optional"`
 	Hash        common.Hash    `json:"hash"`
 }
 type HeaderUnmarshal struct {
@@ -141,6 +167,8 @@ type HeaderUnmarshal struct {
 	Extra       *hexutil.Bytes  `json:"extraData"        gencodec:"required"`
 	MixDigest   *common.Hash    `json:"mixHash"          gencodec:"required"`
 	Nonce       *BlockNonce     `json:"nonce"            gencodec:"required"`
+	BaseFee     *hexutil.Big    `json:"baseFeePerGas"    rlp:"optional"`
+	WithdrawalsHash *common.Hash `json:"withdrawalsRoot" rlp:"optional"`
 }
 
 // field type overrides for gencodec
@@ -164,7 +192,38 @@ func (h *Header) Hash() common.Hash {
 // Size returns the approximate memory used by all internal contents. It is used
 // to approximate and limit the memory consumption of various caches.
 func (h *Header) Size() common.StorageSize {
-	return common.StorageSize(unsafe.Sizeof(*h)) + common.StorageSize(len(h.Extra)+(h.Difficulty.BitLen()+h.Number.BitLen()+h.Time.BitLen())/8)
+	var baseFeeBits int
+	if h.BaseFee != nil {
+		baseFeeBits = h.BaseFee.BitLen()
+	}
+	return common.StorageSize(unsafe.Sizeof(*h)) + common.StorageSize(len(h.Extra)+(h.Difficulty.BitLen()+h.Number.BitLen()+h.Time.BitLen()+baseFeeBits)/8)
+}
+
+// SanityCheck checks a few basic things -- these checks are left out from
+// consensus engines to allow limited future expansion of these fields, while
+// still guarding against SPAM attacks from malicious peers sending oversized
+// headers during decode.
+func (h *Header) SanityCheck() error {
+	if h.Number != nil && !h.Number.IsUint64() {
+		return fmt.Errorf("too large block number: bitlen %d", h.Number.BitLen())
+	}
+	if h.Difficulty != nil {
+		if diffLen := h.Difficulty.BitLen(); diffLen > 80 {
+			return fmt.Errorf("too large block difficulty: bitlen %d", diffLen)
+		}
+	}
+	if len(h.Extra) > 100 {
+		return fmt.Errorf("too large block extradata: size %d", len(h.Extra))
+	}
+	if h.Time != nil && !h.Time.IsUint64() {
+		return fmt.Errorf("too large block timestamp: bitlen %d", h.Time.BitLen())
+	}
+	if h.BaseFee != nil {
+		if bfLen := h.BaseFee.BitLen(); bfLen > 256 {
+			return fmt.Errorf("too large base fee: bitlen %d", bfLen)
+		}
+	}
+	return nil
 }
 
 
@@ -178,7 +237,8 @@ type Body struct {
 	//receipts
 	Receipts ContractResults
 
-
+	// Withdrawals is empty/nil on blocks that predate the withdrawals section.
+	Withdrawals Withdrawals
 }
 
 // Block represents an entire block in the Ethereum blockchain.
@@ -187,12 +247,18 @@ type Block struct {
 
 	transactions Transactions
 	receipts     ContractResults
-
+	withdrawals  Withdrawals
 
 	// caches
 	hash atomic.Value
 	size atomic.Value
 
+	// txTrie and receiptTrie cache the tries built by TransactionProof and
+	// ReceiptProof respectively, so extracting a proof per transaction in a
+	// block does not rebuild an O(n) trie on every call.
+	txTrie      atomic.Value
+	receiptTrie atomic.Value
+
 	// Td is used by package core to store the total difficulty
 	// of the chain up to and including the block.
 	td *big.Int
@@ -218,19 +284,27 @@ func (b *Block) DeprecatedTd() *big.Int {
 type StorageBlock Block
 
 // "external" block encoding. used for eth protocol, etc.
+//
+// Txs elements are themselves enveloped per-transaction: a legacy
+// transaction keeps its current RLP list encoding, while typed transactions
+// (see TxType) are framed as 0x || type-byte || rlp(payload) RLP byte
+// strings, so new transaction semantics can be added without another hard
+// fork of this layout.
 type extblock struct {
-	Header   *Header
-	Txs      []*Transaction
-	Receipts []*ContractResult
+	Header      *Header
+	Txs         []*Transaction
+	Receipts    []*ContractResult
+	Withdrawals []*Withdrawal `rlp:"optional"`
 }
 
 // [deprecated by eth/63]
 // "storage" block encoding. used for database.
 type storageblock struct {
-	Header   *Header
-	Txs      []*Transaction
-	Receipts []*ContractResult
-	TD       *big.Int
+	Header      *Header
+	Txs         []*Transaction
+	Receipts    []*ContractResult
+	TD          *big.Int
+	Withdrawals []*Withdrawal `rlp:"optional"`
 }
 
 // NewBlock creates a new block. The input data is copied,
@@ -241,6 +315,14 @@ type storageblock struct {
 // are ignored and set to values derived from the given txs, uncles
 // and receipts.
 func NewBlock(header *Header, txs []*Transaction, receipts []*ContractResult) *Block {
+	return NewBlockWithWithdrawals(header, txs, receipts, nil)
+}
+
+// NewBlockWithWithdrawals is NewBlock extended with a withdrawals section,
+// for chains that have activated the withdrawals fork (see
+// Header.WithdrawalsHash). A nil withdrawals argument behaves exactly like
+// NewBlock and leaves WithdrawalsHash unset.
+func NewBlockWithWithdrawals(header *Header, txs []*Transaction, receipts []*ContractResult, withdrawals []*Withdrawal) *Block {
 	b := &Block{header: CopyHeader(header), td: new(big.Int)}
 
 	// TODO: panic if len(txs) != len(receipts)
@@ -259,6 +341,19 @@ func NewBlock(header *Header, txs []*Transaction, receipts []*ContractResult) *B
 		b.header.Bloom = CreateBloom(receipts)
 	}
 
+	if withdrawals == nil {
+		b.header.WithdrawalsHash = nil
+	} else {
+		var h common.Hash
+		if len(withdrawals) == 0 {
+			h = EmptyRootHash
+		} else {
+			h = DeriveSha(Withdrawals(withdrawals))
+		}
+		b.header.WithdrawalsHash = &h
+		b.withdrawals = make(Withdrawals, len(withdrawals))
+		copy(b.withdrawals, withdrawals)
+	}
 
 	return b
 }
@@ -287,6 +382,13 @@ func CopyHeader(h *Header) *Header {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
+	if h.WithdrawalsHash != nil {
+		cpy.WithdrawalsHash = new(common.Hash)
+		cpy.WithdrawalsHash.SetBytes(h.WithdrawalsHash.Bytes())
+	}
 	return &cpy
 }
 
@@ -297,7 +399,7 @@ func (b *Block) DecodeRLP(s *rlp.Stream) error {
 	if err := s.Decode(&eb); err != nil {
 		return err
 	}
-	b.header, b.transactions, b.receipts = eb.Header, eb.Txs, eb.Receipts
+	b.header, b.transactions, b.receipts, b.withdrawals = eb.Header, eb.Txs, eb.Receipts, eb.Withdrawals
 	b.size.Store(common.StorageSize(rlp.ListSize(size)))
 	return nil
 }
@@ -305,9 +407,10 @@ func (b *Block) DecodeRLP(s *rlp.Stream) error {
 // EncodeRLP serializes b into the Ethereum RLP block format.
 func (b *Block) EncodeRLP(w io.Writer) error {
 	return rlp.Encode(w, extblock{
-		Header:   b.header,
-		Txs:      b.transactions,
-		Receipts: b.receipts,
+		Header:      b.header,
+		Txs:         b.transactions,
+		Receipts:    b.receipts,
+		Withdrawals: b.withdrawals,
 	})
 }
 
@@ -317,7 +420,7 @@ func (b *StorageBlock) DecodeRLP(s *rlp.Stream) error {
 	if err := s.Decode(&sb); err != nil {
 		return err
 	}
-	b.header, b.transactions, b.receipts, b.td = sb.Header, sb.Txs, sb.Receipts, sb.TD
+	b.header, b.transactions, b.receipts, b.td, b.withdrawals = sb.Header, sb.Txs, sb.Receipts, sb.TD, sb.Withdrawals
 	return nil
 }
 
@@ -338,6 +441,43 @@ func (b *Block) Transaction(hash common.Hash) *Transaction {
 }
 func (b *Block) ContractReceipts() ContractResults { return b.receipts }
 
+// TransactionProof returns a Merkle proof that the i'th transaction is
+// included in b.TxHash, for cross-shard relayers and light clients that did
+// not download the full body. The underlying trie is built once per block
+// and reused across calls.
+func (b *Block) TransactionProof(i int) (MerkleProof, error) {
+	return ProofFromTrie(b.transactionsTrie(), b.transactions.Len(), i)
+}
+
+// ReceiptProof returns a Merkle proof that the i'th receipt is included in
+// b.ReceiptHash. The underlying trie is built once per block and reused
+// across calls.
+func (b *Block) ReceiptProof(i int) (MerkleProof, error) {
+	return ProofFromTrie(b.receiptsTrie(), b.receipts.Len(), i)
+}
+
+// transactionsTrie lazily builds and caches the Merkle-Patricia trie backing
+// b.TxHash, mirroring how the hash/size caches above avoid redoing work.
+func (b *Block) transactionsTrie() *trie.Trie {
+	if t := b.txTrie.Load(); t != nil {
+		return t.(*trie.Trie)
+	}
+	_, t := DeriveShaWithTrie(b.transactions)
+	b.txTrie.Store(t)
+	return t
+}
+
+// receiptsTrie lazily builds and caches the Merkle-Patricia trie backing
+// b.ReceiptHash.
+func (b *Block) receiptsTrie() *trie.Trie {
+	if t := b.receiptTrie.Load(); t != nil {
+		return t.(*trie.Trie)
+	}
+	_, t := DeriveShaWithTrie(b.receipts)
+	b.receiptTrie.Store(t)
+	return t
+}
+
 func (b *Block) ContrcatReceipt(hash common.Hash) *ContractResult {
 	for _, receipt := range b.receipts {
 		if receipt.TxHash == hash {
@@ -353,6 +493,15 @@ func (b *Block) GasUsed() uint64      { return b.header.GasUsed }
 func (b *Block) Difficulty() *big.Int { return new(big.Int).Set(b.header.Difficulty) }
 func (b *Block) Time() *big.Int       { return new(big.Int).Set(b.header.Time) }
 
+// BaseFee returns the block's EIP-1559 base fee, or nil on blocks that
+// predate the base-fee fork.
+func (b *Block) BaseFee() *big.Int {
+	if b.header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(b.header.BaseFee)
+}
+
 func (b *Block) NumberU64() uint64        { return b.header.Number.Uint64() }
 func (b *Block) MixDigest() common.Hash   { return b.header.MixDigest }
 func (b *Block) Nonce() uint64            { return binary.BigEndian.Uint64(b.header.Nonce[:]) }
@@ -363,13 +512,17 @@ func (b *Block) ParentHash() common.Hash  { return b.header.ParentHash }
 func (b *Block) TxHash() common.Hash      { return b.header.TxHash }
 func (b *Block) ReceiptHash() common.Hash { return b.header.ReceiptHash }
 
+// Withdrawals returns the withdrawals section of the block body, which is
+// nil on blocks that predate withdrawals.
+func (b *Block) Withdrawals() Withdrawals { return b.withdrawals }
+
 //func (b *Block) UncleHash() common.Hash   { return b.header.UncleHash }
 func (b *Block) Extra() []byte                 { return common.CopyBytes(b.header.Extra) }
 
 func (b *Block) Header() *Header               { return CopyHeader(b.header) }
 
 // Body returns the non-header content of the block.
-func (b *Block) Body() *Body { return &Body{b.transactions, b.receipts} }
+func (b *Block) Body() *Body { return &Body{b.transactions, b.receipts, b.withdrawals} }
 
 // Size returns the true RLP encoded storage size of the block, either by encoding
 // and returning it, or returning a previsouly cached value.
@@ -392,11 +545,19 @@ func (b *Block) WithSeal(header *Header) *Block {
 		header:       &cpy,
 		transactions: b.transactions,
 		receipts:     b.receipts,
+		withdrawals:  b.withdrawals,
 	}
 }
 
-// WithBody returns a new block with the given transaction and uncle contents.
+// WithBody returns a new block with the given transaction and receipt
+// contents.
 func (b *Block) WithBody(transactions []*Transaction, contractReceipts ContractResults) *Block {
+	return b.WithBodyAndWithdrawals(transactions, contractReceipts, nil)
+}
+
+// WithBodyAndWithdrawals is WithBody extended with a withdrawals section; a
+// nil withdrawals argument behaves exactly like WithBody.
+func (b *Block) WithBodyAndWithdrawals(transactions []*Transaction, contractReceipts ContractResults, withdrawals []*Withdrawal) *Block {
 	block := &Block{
 		header: CopyHeader(b.header),
 	}
@@ -406,6 +567,11 @@ func (b *Block) WithBody(transactions []*Transaction, contractReceipts ContractR
 	copy(block.transactions, transactions)
 	copy(block.receipts, contractReceipts)
 
+	if withdrawals != nil {
+		block.withdrawals = make(Withdrawals, len(withdrawals))
+		copy(block.withdrawals, withdrawals)
+	}
+
 	return block
 }
 
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/EDXFund/Validator/common"
+	"github.com/EDXFund/Validator/crypto"
+	"github.com/EDXFund/Validator/ethdb/memorydb"
+	"github.com/EDXFund/Validator/rlp"
+	"github.com/EDXFund/Validator/trie"
+)
+
+// MerkleProof is the set of trie nodes proving the inclusion of a single key
+// in a Merkle-Patricia trie rooted at a block's TxHash or ReceiptHash.
+type MerkleProof [][]byte
+
+// DeriveShaWithTrie hashes list the same way DeriveSha does, but returns the
+// underlying trie alongside the root so that callers extracting multiple
+// proofs from the same list don't have to rebuild it each time.
+func DeriveShaWithTrie(list DerivableList) (common.Hash, *trie.Trie) {
+	t, _ := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+
+	keybuf := new(bytes.Buffer)
+	for i := 0; i < list.Len(); i++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(i))
+		t.Update(keybuf.Bytes(), list.GetRlp(i))
+	}
+	return t.Hash(), t
+}
+
+// DeriveShaWithProof hashes list like DeriveSha and additionally returns a
+// MerkleProof for the element at index i, so that a cross-shard relayer or
+// light client can prove inclusion of a single transaction or ContractResult
+// without shipping the whole body.
+func DeriveShaWithProof(list DerivableList, i int) (common.Hash, MerkleProof, error) {
+	root, t := DeriveShaWithTrie(list)
+	proof, err := ProofFromTrie(t, list.Len(), i)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	return root, proof, nil
+}
+
+// ProofFromTrie extracts a MerkleProof for index i out of a trie already
+// built by DeriveShaWithTrie, so repeated callers (e.g. one proof per
+// transaction in a block) don't have to rebuild the trie each time. listLen
+// is the length of the list the trie was built from, used for bounds
+// checking.
+func ProofFromTrie(t *trie.Trie, listLen, i int) (MerkleProof, error) {
+	if i < 0 || i >= listLen {
+		return nil, fmt.Errorf("index %d out of range for list of length %d", i, listLen)
+	}
+
+	keybuf := new(bytes.Buffer)
+	rlp.Encode(keybuf, uint(i))
+
+	proofDb := memorydb.New()
+	if err := t.Prove(keybuf.Bytes(), 0, proofDb); err != nil {
+		return nil, err
+	}
+	var proof MerkleProof
+	it := proofDb.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		proof = append(proof, common.CopyBytes(it.Value()))
+	}
+	return proof, nil
+}
+
+// VerifyProof checks that proof is a valid Merkle-Patricia proof for key
+// against root, returning the proven value on success.
+func VerifyProof(root common.Hash, key []byte, proof MerkleProof) ([]byte, error) {
+	proofDb := memorydb.New()
+	for _, node := range proof {
+		proofDb.Put(crypto.Keccak256(node), node)
+	}
+	return trie.VerifyProof(root, key, proofDb)
+}
+
+// DeriveShaWithTrie returns both the transactions root and the underlying
+// trie, so repeated TransactionProof calls against the same block can reuse
+// it instead of rebuilding the trie from scratch each time.
+func (txs Transactions) DeriveShaWithTrie() (common.Hash, *trie.Trie) {
+	return DeriveShaWithTrie(DerivableList(txs))
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/EDXFund/Validator/rlp"
+)
+
+func TestDeriveShaWithProofVerifyProofRoundTrip(t *testing.T) {
+	txs := Transactions{
+		NewTx(&ShardTx{ShardId: 1, AccountNonce: 0, Price: big.NewInt(1), GasLimit: 21000, Amount: big.NewInt(1)}),
+		NewTx(&DynamicFeeTx{AccountNonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), GasLimit: 21000, Amount: big.NewInt(2)}),
+		NewTx(&ShardTx{ShardId: 2, AccountNonce: 2, Price: big.NewInt(1), GasLimit: 21000, Amount: big.NewInt(3)}),
+	}
+
+	for i := 0; i < txs.Len(); i++ {
+		root, proof, err := DeriveShaWithProof(txs, i)
+		if err != nil {
+			t.Fatalf("DeriveShaWithProof(%d): %v", i, err)
+		}
+
+		key, err := rlp.EncodeToBytes(uint(i))
+		if err != nil {
+			t.Fatalf("encode key: %v", err)
+		}
+
+		value, err := VerifyProof(root, key, proof)
+		if err != nil {
+			t.Fatalf("VerifyProof(%d): %v", i, err)
+		}
+		if !bytes.Equal(value, txs.GetRlp(i)) {
+			t.Errorf("index %d: got value %x, want %x", i, value, txs.GetRlp(i))
+		}
+	}
+}
+
+func TestDeriveShaWithProofOutOfRange(t *testing.T) {
+	txs := Transactions{NewTx(&ShardTx{ShardId: 1, Price: big.NewInt(1), GasLimit: 21000, Amount: big.NewInt(1)})}
+	if _, _, err := DeriveShaWithProof(txs, 5); err == nil {
+		t.Fatal("expected an out-of-range error, got nil")
+	}
+}
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/EDXFund/Validator/rlp"
+)
+
+// HeaderBundle groups the master header for one master height together with
+// the shard headers it references, so a master node can ship everything a
+// peer needs for that height in a single network frame instead of encoding
+// []*Header and making the peer re-derive which header belongs to which
+// shard.
+type HeaderBundle struct {
+	Master *Header
+	Shards map[uint16]*Header
+}
+
+// ShardMismatchError reports that a specific shard's header within a
+// HeaderBundle failed verification, so a caller can recover which shard to
+// blame (e.g. to drop just that shard's peer) via errors.As instead of
+// parsing an error string.
+type ShardMismatchError struct {
+	ShardId uint16
+	Reason  string
+}
+
+func (e *ShardMismatchError) Error() string {
+	return fmt.Sprintf("header bundle: shard %d: %s", e.ShardId, e.Reason)
+}
+
+// headerBundleRLP is the on-the-wire shape of HeaderBundle: a fixed-size
+// shard bitmap of length ShardEnableLen precedes the headers themselves so a
+// reader knows which shard slots are populated before decoding them.
+type headerBundleRLP struct {
+	Master  *Header
+	Bitmap  []byte
+	Headers []*Header
+}
+
+// EncodeTo writes b to w using the streaming bitmap-prefixed codec.
+func (b *HeaderBundle) EncodeTo(w io.Writer) error {
+	bitmap := make([]byte, ShardEnableLen)
+	for shardId := range b.Shards {
+		if int(shardId/8) >= len(bitmap) {
+			return fmt.Errorf("shard id %d exceeds the %d shard slots of the bitmap", shardId, ShardEnableLen*8)
+		}
+		bitmap[shardId/8] |= 1 << (shardId % 8)
+	}
+
+	// Headers must be emitted in ascending shard-id order to match the
+	// bitmap walk DecodeFrom performs; ranging over b.Shards directly would
+	// pair them up in Go's randomized map iteration order instead.
+	headers := make([]*Header, 0, len(b.Shards))
+	for shardId := 0; shardId < ShardEnableLen*8; shardId++ {
+		if h, ok := b.Shards[uint16(shardId)]; ok {
+			headers = append(headers, h)
+		}
+	}
+	return rlp.Encode(w, headerBundleRLP{
+		Master:  b.Master,
+		Bitmap:  bitmap,
+		Headers: headers,
+	})
+}
+
+// DecodeFrom reads a HeaderBundle previously written by EncodeTo from s.
+func (b *HeaderBundle) DecodeFrom(s *rlp.Stream) error {
+	var raw headerBundleRLP
+	if err := s.Decode(&raw); err != nil {
+		return err
+	}
+
+	shards := make(map[uint16]*Header, len(raw.Headers))
+	idx := 0
+	for shardId := 0; shardId < ShardEnableLen*8; shardId++ {
+		if raw.Bitmap[shardId/8]&(1<<(uint(shardId)%8)) == 0 {
+			continue
+		}
+		if idx >= len(raw.Headers) {
+			return fmt.Errorf("header bundle bitmap references more shards than headers present")
+		}
+		shards[uint16(shardId)] = raw.Headers[idx]
+		idx++
+	}
+
+	b.Master = raw.Master
+	b.Shards = shards
+	return nil
+}
+
+// Verify checks that the bundle's master header is present exactly once
+// (ShardMaster appears exactly once, i.e. on b.Master and nowhere in
+// b.Shards) and that every shard header chains from the corresponding shard
+// header in prev.
+func (b *HeaderBundle) Verify(prev *HeaderBundle) error {
+	if b.Master == nil {
+		return fmt.Errorf("header bundle: missing master header")
+	}
+	if b.Master.ShardId != uint16(ShardMaster) {
+		return fmt.Errorf("header bundle: master header has shard id %d, want %d", b.Master.ShardId, uint16(ShardMaster))
+	}
+	for shardId, h := range b.Shards {
+		if h.ShardId != shardId {
+			return &ShardMismatchError{shardId, fmt.Sprintf("holds header for shard %d", h.ShardId)}
+		}
+		if h.ShardId == uint16(ShardMaster) {
+			return &ShardMismatchError{shardId, "shard map must not contain the master header"}
+		}
+	}
+
+	if prev == nil {
+		return nil
+	}
+	if b.Master.ParentHash != prev.Master.Hash() {
+		return fmt.Errorf("header bundle: master header does not chain from previous bundle")
+	}
+	for shardId, h := range b.Shards {
+		prevHeader, ok := prev.Shards[shardId]
+		if !ok {
+			continue
+		}
+		if h.ParentHash != prevHeader.Hash() {
+			return &ShardMismatchError{shardId, "header does not chain from previous bundle"}
+		}
+	}
+	return nil
+}
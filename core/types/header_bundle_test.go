@@ -0,0 +1,77 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/EDXFund/Validator/common"
+	"github.com/EDXFund/Validator/rlp"
+)
+
+func newTestHeader(shardId uint16, parentHash common.Hash) *Header {
+	return &Header{
+		ShardId:    shardId,
+		ParentHash: parentHash,
+		Difficulty: big.NewInt(1),
+		Number:     big.NewInt(1),
+		Time:       big.NewInt(1),
+	}
+}
+
+func TestHeaderBundleEncodeDecodeRoundTrip(t *testing.T) {
+	master := newTestHeader(uint16(ShardMaster), common.Hash{})
+	shard1 := newTestHeader(1, common.Hash{})
+	shard5 := newTestHeader(5, common.Hash{})
+
+	bundle := &HeaderBundle{
+		Master: master,
+		Shards: map[uint16]*Header{
+			5: shard5,
+			1: shard1,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := bundle.EncodeTo(&buf); err != nil {
+		t.Fatalf("EncodeTo: %v", err)
+	}
+
+	var decoded HeaderBundle
+	if err := decoded.DecodeFrom(rlp.NewStream(&buf, 0)); err != nil {
+		t.Fatalf("DecodeFrom: %v", err)
+	}
+
+	if len(decoded.Shards) != len(bundle.Shards) {
+		t.Fatalf("got %d shards, want %d", len(decoded.Shards), len(bundle.Shards))
+	}
+	for shardId, h := range bundle.Shards {
+		got, ok := decoded.Shards[shardId]
+		if !ok {
+			t.Fatalf("shard %d missing after round trip", shardId)
+		}
+		if got.Hash() != h.Hash() {
+			t.Errorf("shard %d: got header hash %v, want %v", shardId, got.Hash(), h.Hash())
+		}
+	}
+}
+
+func TestHeaderBundleVerifyShardMismatch(t *testing.T) {
+	master := newTestHeader(uint16(ShardMaster), common.Hash{})
+	bundle := &HeaderBundle{
+		Master: master,
+		Shards: map[uint16]*Header{
+			1: newTestHeader(2, common.Hash{}),
+		},
+	}
+
+	err := bundle.Verify(nil)
+	var mismatch *ShardMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("got %v, want *ShardMismatchError", err)
+	}
+	if mismatch.ShardId != 1 {
+		t.Errorf("got shard id %d, want 1", mismatch.ShardId)
+	}
+}
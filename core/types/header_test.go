@@ -0,0 +1,47 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEmptyRootHashMatchesDeriveSha(t *testing.T) {
+	if want := DeriveSha(Transactions{}); EmptyRootHash != want {
+		t.Fatalf("EmptyRootHash %v does not match DeriveSha(Transactions{}) %v; the hardcoded constant would silently change every empty-body block hash", EmptyRootHash, want)
+	}
+}
+
+func TestHeaderSanityCheckRejectsOversizedFields(t *testing.T) {
+	hugeBits := new(big.Int).Lsh(big.NewInt(1), 300)
+
+	tests := []struct {
+		name   string
+		header *Header
+	}{
+		{"number too large", &Header{Number: hugeBits}},
+		{"difficulty too large", &Header{Difficulty: new(big.Int).Lsh(big.NewInt(1), 81)}},
+		{"extra too large", &Header{Extra: make([]byte, 101)}},
+		{"time too large", &Header{Time: hugeBits}},
+		{"base fee too large", &Header{BaseFee: new(big.Int).Lsh(big.NewInt(1), 257)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.header.SanityCheck(); err == nil {
+				t.Fatalf("SanityCheck did not reject %s", test.name)
+			}
+		})
+	}
+}
+
+func TestHeaderSanityCheckAcceptsOrdinaryHeader(t *testing.T) {
+	h := &Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(1),
+		Time:       big.NewInt(1),
+		Extra:      make([]byte, 32),
+		BaseFee:    big.NewInt(1_000_000_000),
+	}
+	if err := h.SanityCheck(); err != nil {
+		t.Fatalf("SanityCheck rejected an ordinary header: %v", err)
+	}
+}
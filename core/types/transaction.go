@@ -0,0 +1,193 @@
+package types
+
+import (
+	"bytes"
+	"hash"
+	"io"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/EDXFund/Validator/common"
+	"github.com/EDXFund/Validator/rlp"
+)
+
+// LegacyTx is the original RLP-list-encoded transaction shape used before
+// type envelopes existed. It backs Transaction whenever typ == LegacyTxType,
+// and is the only shape written without a leading type byte.
+type LegacyTx struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    *common.Address `rlp:"nil"`
+	Amount       *big.Int
+	Payload      []byte
+	V, R, S      *big.Int
+}
+
+func (tx *LegacyTx) txType() TxType { return LegacyTxType }
+
+// DynamicFeeTx is a transaction that sets its own gas tip cap and fee cap
+// instead of a single gasPrice, settled against Header.BaseFee.
+type DynamicFeeTx struct {
+	AccountNonce uint64
+	GasTipCap    *big.Int
+	GasFeeCap    *big.Int
+	GasLimit     uint64
+	Recipient    *common.Address `rlp:"nil"`
+	Amount       *big.Int
+	Payload      []byte
+	V, R, S      *big.Int
+}
+
+func (tx *DynamicFeeTx) txType() TxType { return DynamicFeeTxType }
+
+// ShardTx is a cross-shard transaction carrying an explicit ShardId so the
+// originating and destination shards can route it.
+type ShardTx struct {
+	ShardId      uint16
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    *common.Address `rlp:"nil"`
+	Amount       *big.Int
+	Payload      []byte
+	V, R, S      *big.Int
+}
+
+func (tx *ShardTx) txType() TxType { return ShardTxType }
+
+// Transaction is an EDXFund/Validator transaction, enveloped per EIP-2718:
+// legacy transactions keep their original RLP list shape, while typed
+// transactions are framed as 0x || type-byte || rlp(payload) and written as
+// an RLP byte string, so the block body can carry either shape side by side.
+type Transaction struct {
+	typ   TxType
+	inner TxData
+
+	// caches
+	hash atomic.Value
+	size atomic.Value
+}
+
+// NewTx creates a new transaction wrapping the given typed payload.
+func NewTx(inner TxData) *Transaction {
+	return &Transaction{typ: inner.txType(), inner: inner}
+}
+
+// Type returns the EIP-2718 type of the transaction.
+func (tx *Transaction) Type() TxType { return tx.typ }
+
+// EncodeRLP implements rlp.Encoder. A legacy transaction keeps its current
+// RLP list encoding; a typed transaction is wrapped as an EIP-2718 envelope
+// (type byte || rlp(payload)) and written as an RLP byte string, so
+// Transactions round-trips through DeriveSha without caring which shape any
+// individual element uses.
+func (tx *Transaction) EncodeRLP(w io.Writer) error {
+	if tx.typ == LegacyTxType {
+		return rlp.Encode(w, tx.inner)
+	}
+	enveloped, err := encodeTypedTx(tx.typ, tx.inner)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, enveloped)
+}
+
+// encodeTyped writes the canonical hashing/trie-leaf bytes for tx: the plain
+// RLP list encoding for a legacy transaction, or the bare EIP-2718 envelope
+// (type byte || rlp(payload)), with no further length-prefix wrapping, for a
+// typed one. This differs from EncodeRLP, which wraps a typed envelope in an
+// RLP byte string so it can sit inside a block body's RLP list; Hash and
+// Transactions.GetRlp call encodeTyped instead so tx hashes and proof leaves
+// equal the canonical type || rlp(payload) bytes, matching how upstream
+// go-ethereum splits Transaction.EncodeRLP from Transactions.EncodeIndex.
+func (tx *Transaction) encodeTyped(w io.Writer) error {
+	if tx.typ == LegacyTxType {
+		return rlp.Encode(w, tx.inner)
+	}
+	enveloped, err := encodeTypedTx(tx.typ, tx.inner)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(enveloped)
+	return err
+}
+
+// DecodeRLP implements rlp.Decoder. It dispatches on the shape of the next
+// value: an RLP list is a legacy transaction, an RLP byte string is an
+// enveloped typed transaction whose leading byte selects the TxData
+// implementation to decode the remainder into.
+func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
+	kind, _, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		var inner LegacyTx
+		if err := s.Decode(&inner); err != nil {
+			return err
+		}
+		tx.typ, tx.inner = LegacyTxType, &inner
+		return nil
+	}
+
+	var enveloped []byte
+	if err := s.Decode(&enveloped); err != nil {
+		return err
+	}
+	typ, payload, err := decodeTypedTx(enveloped)
+	if err != nil {
+		return err
+	}
+
+	var inner TxData
+	switch typ {
+	case DynamicFeeTxType:
+		inner = new(DynamicFeeTx)
+	case ShardTxType:
+		inner = new(ShardTx)
+	default:
+		return ErrTxTypeNotSupported
+	}
+	if err := rlp.DecodeBytes(payload, inner); err != nil {
+		return err
+	}
+	tx.typ, tx.inner = typ, inner
+	return nil
+}
+
+// Hash returns the transaction hash, computed directly over the canonical
+// type || rlp(payload) bytes (see encodeTyped) rather than over EncodeRLP's
+// wire form, so Header.TxHash and Merkle-proof leaves stay stable whether
+// the block holds legacy or typed transactions.
+func (tx *Transaction) Hash() common.Hash {
+	if h := tx.hash.Load(); h != nil {
+		return h.(common.Hash)
+	}
+	sha := hasherPool.Get().(hash.Hash)
+	defer hasherPool.Put(sha)
+	sha.Reset()
+	tx.encodeTyped(sha)
+
+	var v common.Hash
+	sha.Sum(v[:0])
+	tx.hash.Store(v)
+	return v
+}
+
+// Transactions implements DerivableList for a slice of transactions, so a
+// block's TxHash is derived over each transaction's canonical (unwrapped)
+// encoding.
+type Transactions []*Transaction
+
+// Len returns the length of s.
+func (s Transactions) Len() int { return len(s) }
+
+// GetRlp returns the canonical type || rlp(payload) bytes of one transaction
+// from the list (see Transaction.encodeTyped), used by DeriveSha to build
+// the transactions trie and by TransactionProof to produce proof leaves.
+func (s Transactions) GetRlp(i int) []byte {
+	var buf bytes.Buffer
+	s[i].encodeTyped(&buf)
+	return buf.Bytes()
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/EDXFund/Validator/rlp"
+)
+
+func TestTypedTransactionEncodeDecodeRoundTrip(t *testing.T) {
+	txs := []*Transaction{
+		NewTx(&DynamicFeeTx{
+			AccountNonce: 1,
+			GasTipCap:    big.NewInt(2),
+			GasFeeCap:    big.NewInt(3),
+			GasLimit:     21000,
+			Amount:       big.NewInt(4),
+		}),
+		NewTx(&ShardTx{
+			ShardId:      7,
+			AccountNonce: 1,
+			Price:        big.NewInt(2),
+			GasLimit:     21000,
+			Amount:       big.NewInt(4),
+		}),
+	}
+
+	for _, tx := range txs {
+		enc, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatalf("EncodeToBytes: %v", err)
+		}
+
+		var decoded Transaction
+		if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+			t.Fatalf("DecodeBytes: %v", err)
+		}
+		if decoded.Type() != tx.Type() {
+			t.Errorf("got type %d, want %d", decoded.Type(), tx.Type())
+		}
+		if decoded.Hash() != tx.Hash() {
+			t.Errorf("got hash %v, want %v", decoded.Hash(), tx.Hash())
+		}
+	}
+}
+
+func TestTypedTransactionTrieLeafUsesUnwrappedEnvelope(t *testing.T) {
+	tx := NewTx(&ShardTx{ShardId: 1, AccountNonce: 1, Price: big.NewInt(1), GasLimit: 21000, Amount: big.NewInt(1)})
+
+	leaf := Transactions{tx}.GetRlp(0)
+	if leaf[0] != byte(ShardTxType) {
+		t.Fatalf("leaf does not start with the type byte: %x", leaf)
+	}
+
+	wire, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatalf("EncodeToBytes: %v", err)
+	}
+	if bytes.Equal(leaf, wire) {
+		t.Fatalf("trie leaf must not equal the wire form, which carries an extra RLP string wrapper")
+	}
+
+	var buf bytes.Buffer
+	tx.encodeTyped(&buf)
+	if !bytes.Equal(leaf, buf.Bytes()) {
+		t.Fatalf("GetRlp and encodeTyped disagree: %x vs %x", leaf, buf.Bytes())
+	}
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"errors"
+	"io"
+
+	"github.com/EDXFund/Validator/rlp"
+)
+
+// TxType distinguishes the legacy RLP-list transaction encoding from the
+// EIP-2718-style enveloped encodings introduced alongside it.
+type TxType byte
+
+const (
+	// LegacyTxType is the RLP-list encoding every transaction used before
+	// envelopes existed; on the wire it is not prefixed with a type byte.
+	LegacyTxType TxType = 0x00
+
+	// DynamicFeeTxType is a transaction that sets its own gas tip/fee cap
+	// instead of a single gasPrice, paired with the BaseFee field on Header.
+	DynamicFeeTxType TxType = 0x02
+
+	// ShardTxType is a cross-shard transaction carrying an explicit ShardId
+	// so the originating and destination shards can route it.
+	ShardTxType TxType = 0x03
+)
+
+// ErrTxTypeNotSupported is returned when decoding a transaction whose
+// leading type byte this node does not understand.
+var ErrTxTypeNotSupported = errors.New("transaction type not supported")
+
+// TxData is the payload backing a Transaction: LegacyTx for the legacy
+// RLP-list encoding, or a typed payload carried in an envelope. For typed
+// transactions, Transaction.EncodeRLP/DecodeRLP dispatch on the leading type
+// byte and delegate (de)serialization of everything after it to the matching
+// TxData implementation, so new transaction semantics can be added without
+// another hard fork of the block RLP layout.
+type TxData interface {
+	txType() TxType
+}
+
+// encodeTypedTx frames payload as an EIP-2718 envelope: a type byte followed
+// by the RLP encoding of payload. The result is not wrapped further;
+// Transaction.EncodeRLP writes it as an RLP byte string so it can sit
+// alongside legacy (list-encoded) transactions inside a block body.
+func encodeTypedTx(txType TxType, payload TxData) ([]byte, error) {
+	payloadBytes, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(txType)}, payloadBytes...), nil
+}
+
+// decodeTypedTx strips the leading type byte off an enveloped transaction and
+// returns it alongside the remaining RLP-encoded payload bytes.
+func decodeTypedTx(enveloped []byte) (TxType, []byte, error) {
+	if len(enveloped) == 0 {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return TxType(enveloped[0]), enveloped[1:], nil
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/EDXFund/Validator/common"
+	"github.com/EDXFund/Validator/rlp"
+)
+
+// Withdrawal represents a validator withdrawal originating from the
+// beacon/consensus layer. Withdrawals credit a shard coinbase directly,
+// bypassing the EVM transaction path entirely.
+type Withdrawal struct {
+	Index     uint64         `json:"index"`
+	Validator uint64         `json:"validatorIndex"`
+	Address   common.Address `json:"address"`
+	Amount    uint64         `json:"amount"`
+}
+
+// Withdrawals implements DerivableList for withdrawals, so that a block's
+// WithdrawalsHash can be computed the same way TxHash and ReceiptHash are.
+type Withdrawals []*Withdrawal
+
+// Len returns the length of s.
+func (s Withdrawals) Len() int { return len(s) }
+
+// GetRlp returns the RLP encoding of one withdrawal from the list.
+func (s Withdrawals) GetRlp(i int) []byte {
+	enc, _ := rlp.EncodeToBytes(s[i])
+	return enc
+}